@@ -340,6 +340,65 @@ func BenchmarkEstimateFPP(b *testing.B) {
 	}
 }
 
+func benchmarkItems(n int) [][]byte {
+	items := make([][]byte, n)
+	for i := range items {
+		items[i] = make([]byte, 32)
+		rand.Read(items[i])
+	}
+	return items
+}
+
+// BenchmarkAddRejectionSampling measures Add using the original per-hash-call
+// rejection-sampling loop (NewFilter with a HashFunction[T]).
+func BenchmarkAddRejectionSampling(b *testing.B) {
+	items := benchmarkItems(b.N)
+	f, _ := NewFilter(1<<20, 7, XXH3)
+	b.ResetTimer()
+	for _, item := range items {
+		f.Add(item)
+	}
+}
+
+// BenchmarkAddKirschMitzenmacher measures Add using the Kirsch-Mitzenmacher fast
+// path (NewFilter128), which hashes data once per call instead of once per index.
+func BenchmarkAddKirschMitzenmacher(b *testing.B) {
+	items := benchmarkItems(b.N)
+	f, _ := NewFilter128(1<<20, 7, xxh3Hash128)
+	b.ResetTimer()
+	for _, item := range items {
+		f.Add(item)
+	}
+}
+
+// BenchmarkTestRejectionSampling measures Test using the original rejection-sampling
+// loop.
+func BenchmarkTestRejectionSampling(b *testing.B) {
+	items := benchmarkItems(b.N)
+	f, _ := NewFilter(1<<20, 7, XXH3)
+	for _, item := range items {
+		f.Add(item)
+	}
+	b.ResetTimer()
+	for _, item := range items {
+		f.Test(item)
+	}
+}
+
+// BenchmarkTestKirschMitzenmacher measures Test using the Kirsch-Mitzenmacher fast
+// path.
+func BenchmarkTestKirschMitzenmacher(b *testing.B) {
+	items := benchmarkItems(b.N)
+	f, _ := NewFilter128(1<<20, 7, xxh3Hash128)
+	for _, item := range items {
+		f.Add(item)
+	}
+	b.ResetTimer()
+	for _, item := range items {
+		f.Test(item)
+	}
+}
+
 func TestLargeNotPowerOfTwo(t *testing.T) {
 	// Not a power of 2
 	f, _ := NewXXH3Filter(9, 10)
@@ -371,6 +430,54 @@ func TestEstimatedEntries(t *testing.T) {
 	}
 }
 
+func TestFilter128Basic(t *testing.T) {
+	f, _ := NewFilter128(1000, 4, xxh3Hash128)
+
+	n1 := []byte("one")
+	n2 := []byte("two")
+	n3 := []byte("three")
+	f.Add(n1)
+	n3a := f.Test(n3)
+	f.Add(n3)
+	n1b := f.Test(n1)
+	n2b := f.Test(n2)
+	n3b := f.Test(n3)
+	if !n1b {
+		t.Errorf("%v should be in.", n1)
+	}
+	if n2b {
+		t.Errorf("%v should not be in.", n2)
+	}
+	if n3a {
+		t.Errorf("%v should not be in the first time we look.", n3)
+	}
+	if !n3b {
+		t.Errorf("%v should be in the second time we look.", n3)
+	}
+}
+
+func TestFilter128NotPowerOfTwo(t *testing.T) {
+	f, _ := NewFilter128(9, 10, xxh3Hash128)
+	for i := 0; i < 8; i++ {
+		item := make([]byte, 4)
+		rand.Read(item)
+		f.Add(item)
+		if !f.Test(item) {
+			t.Errorf("should always return true for something added, i=%v, item=%v", i, item)
+		}
+	}
+}
+
+func TestFilter128IncompatibleHashFunctionsUnion(t *testing.T) {
+	f1, _ := NewFilter128(128, 3, xxh3Hash128)
+	f1.Add([]byte{1})
+	f2, _ := NewFilter(128, 3, XXH3)
+	f2.Add([]byte{2})
+	if err := f1.Union(f2); err != ERR_INCOMPATIBLE_HASH_FUNCTIONS {
+		t.Errorf("should return ERR_INCOMPATIBLE_HASH_FUNCTIONS")
+	}
+}
+
 func TestEstimatedCapacity(t *testing.T) {
 	for i := 0; i < 200; i++ {
 		capacity := mrand.Intn(1024) * 100
@@ -0,0 +1,212 @@
+package bloom
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/fission-codes/go-bitset"
+)
+
+// filterMagic identifies the start of a serialized Filter on the wire.
+var filterMagic = [4]byte{'B', 'L', 'M', 'F'}
+
+// filterVersion is the wire format version written by WriteTo and expected by ReadFrom.
+const filterVersion uint8 = 1
+
+// headerSize is the length, in bytes, of the magic/version/bitCount/hashCount header
+// that precedes the packed bit-array on the wire.
+const headerSize = len(filterMagic) + 1 + 8 + 8
+
+var ERR_INVALID_MAGIC = errors.New("invalid Bloom filter magic bytes")
+var ERR_UNSUPPORTED_VERSION = errors.New("unsupported Bloom filter wire format version")
+var ERR_SHORT_BYTES = errors.New("not enough bytes to hold bitCount bits")
+
+// SetHashFunction attaches function to the filter, clearing any HashFunction128 set
+// by SetHashFunction128. The HashFunction cannot be part of the wire format produced
+// by WriteTo/MarshalBinary/MarshalJSON/GobEncode, so callers decoding a filter with
+// ReadFrom, UnmarshalBinary, UnmarshalJSON, or GobDecode must call SetHashFunction (or
+// SetHashFunction128) before using Add or Test.
+func (f *Filter[T]) SetHashFunction(function HashFunction[T]) *Filter[T] {
+	f.function = function
+	f.function128 = nil
+	return f
+}
+
+// SetHashFunction128 attaches function to the filter, clearing any HashFunction set
+// by SetHashFunction, and switches the filter to the Kirsch-Mitzenmacher fast path
+// (see NewFilter128).
+func (f *Filter[T]) SetHashFunction128(function HashFunction128[T]) *Filter[T] {
+	f.function128 = function
+	f.function = nil
+	return f
+}
+
+// encode serializes the filter's bitCount, hashCount, and bit-array bytes behind a
+// magic/version header. The HashFunction is not included.
+func (f *Filter[T]) encode() []byte {
+	var buf bytes.Buffer
+	buf.Grow(headerSize + len(f.Bytes()))
+	buf.Write(filterMagic[:])
+	buf.WriteByte(filterVersion)
+	binary.Write(&buf, binary.BigEndian, f.bitCount)
+	binary.Write(&buf, binary.BigEndian, f.hashCount)
+	buf.Write(f.Bytes())
+	return buf.Bytes()
+}
+
+// decode parses data written by encode, replacing this filter's bitCount, hashCount,
+// and bit-array. It validates that data carries enough bytes to hold bitCount bits
+// before allocating the bit-array.
+func (f *Filter[T]) decode(data []byte) error {
+	if len(data) < headerSize {
+		return ERR_SHORT_BYTES
+	}
+	if !bytes.Equal(data[:len(filterMagic)], filterMagic[:]) {
+		return ERR_INVALID_MAGIC
+	}
+	offset := len(filterMagic)
+
+	version := data[offset]
+	offset++
+	if version != filterVersion {
+		return ERR_UNSUPPORTED_VERSION
+	}
+
+	bitCount := binary.BigEndian.Uint64(data[offset : offset+8])
+	offset += 8
+	hashCount := binary.BigEndian.Uint64(data[offset : offset+8])
+	offset += 8
+
+	bloomBytes := data[offset:]
+	if uint64(len(bloomBytes))*8 < bitCount {
+		return ERR_SHORT_BYTES
+	}
+
+	f.bitCount = max(1, bitCount)
+	f.hashCount = max(1, hashCount)
+	f.bitSet = bitset.NewFromBytes(f.bitCount, bloomBytes)
+	return nil
+}
+
+// WriteTo writes the filter to w as a magic/version header followed by bitCount,
+// hashCount, and the packed bit-array bytes, so the filter can round-trip through
+// disk, gRPC, or other io.Writer/io.Reader pipes. It satisfies io.WriterTo.
+func (f *Filter[T]) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(f.encode())
+	return int64(n), err
+}
+
+// ReadFrom reads a filter previously written by WriteTo, replacing this filter's
+// bitCount, hashCount, and bit-array. The HashFunction cannot be serialized, so
+// callers must call SetHashFunction afterward before using Add or Test. It satisfies
+// io.ReaderFrom. Unlike UnmarshalBinary, ReadFrom consumes exactly the bytes that
+// make up the filter, so multiple filters can be written to and read back from the
+// same stream in sequence.
+func (f *Filter[T]) ReadFrom(r io.Reader) (int64, error) {
+	var header [headerSize]byte
+	n, err := io.ReadFull(r, header[:])
+	total := int64(n)
+	if err != nil {
+		return total, err
+	}
+
+	if !bytes.Equal(header[:len(filterMagic)], filterMagic[:]) {
+		return total, ERR_INVALID_MAGIC
+	}
+	offset := len(filterMagic)
+
+	version := header[offset]
+	offset++
+	if version != filterVersion {
+		return total, ERR_UNSUPPORTED_VERSION
+	}
+
+	bitCount := binary.BigEndian.Uint64(header[offset : offset+8])
+	offset += 8
+	hashCount := binary.BigEndian.Uint64(header[offset : offset+8])
+
+	bloomBytes := make([]byte, (bitCount+7)/8)
+	m, err := io.ReadFull(r, bloomBytes)
+	total += int64(m)
+	if err != nil {
+		return total, err
+	}
+
+	f.bitCount = max(1, bitCount)
+	f.hashCount = max(1, hashCount)
+	f.bitSet = bitset.NewFromBytes(f.bitCount, bloomBytes)
+	return total, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler using the same wire format as
+// WriteTo.
+func (f *Filter[T]) MarshalBinary() ([]byte, error) {
+	return f.encode(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler using the same wire format as
+// WriteTo. As with ReadFrom, callers must call SetHashFunction afterward before using
+// Add or Test.
+func (f *Filter[T]) UnmarshalBinary(data []byte) error {
+	return f.decode(data)
+}
+
+// GobEncode implements gob.GobEncoder using the same wire format as WriteTo.
+func (f *Filter[T]) GobEncode() ([]byte, error) {
+	return f.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder using the same wire format as WriteTo. As with
+// ReadFrom, callers must call SetHashFunction afterward before using Add or Test.
+func (f *Filter[T]) GobDecode(data []byte) error {
+	return f.UnmarshalBinary(data)
+}
+
+// filterJSON is the JSON representation of a Filter: bitCount and hashCount as
+// numbers, and the packed bit-array base64-encoded.
+type filterJSON struct {
+	Version   uint8  `json:"version"`
+	BitCount  uint64 `json:"bitCount"`
+	HashCount uint64 `json:"hashCount"`
+	Bits      string `json:"bits"`
+}
+
+// MarshalJSON implements json.Marshaler. The bit-array is base64-encoded; the
+// HashFunction is not included.
+func (f *Filter[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(filterJSON{
+		Version:   filterVersion,
+		BitCount:  f.bitCount,
+		HashCount: f.hashCount,
+		Bits:      base64.StdEncoding.EncodeToString(f.Bytes()),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. As with ReadFrom, callers must call
+// SetHashFunction afterward before using Add or Test.
+func (f *Filter[T]) UnmarshalJSON(data []byte) error {
+	var j filterJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Version != filterVersion {
+		return ERR_UNSUPPORTED_VERSION
+	}
+
+	bloomBytes, err := base64.StdEncoding.DecodeString(j.Bits)
+	if err != nil {
+		return err
+	}
+	if uint64(len(bloomBytes))*8 < j.BitCount {
+		return ERR_SHORT_BYTES
+	}
+
+	f.bitCount = max(1, j.BitCount)
+	f.hashCount = max(1, j.HashCount)
+	f.bitSet = bitset.NewFromBytes(f.bitCount, bloomBytes)
+	return nil
+}
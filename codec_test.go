@@ -0,0 +1,161 @@
+package bloom
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteToReadFrom(t *testing.T) {
+	f1, _ := NewXXH3Filter(1000, 4)
+	f1.Add([]byte("one"))
+	f1.Add([]byte("two"))
+
+	var buf bytes.Buffer
+	n, err := f1.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo reported %v bytes, buffer has %v", n, buf.Len())
+	}
+
+	f2, _ := NewXXH3Filter(1, 1)
+	if _, err := f2.ReadFrom(&buf); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	f2.SetHashFunction128(xxh3Hash128)
+
+	if f2.BitCount() != f1.BitCount() || f2.HashCount() != f1.HashCount() {
+		t.Errorf("round-tripped filter has different dimensions: got (%v, %v), want (%v, %v)", f2.BitCount(), f2.HashCount(), f1.BitCount(), f1.HashCount())
+	}
+	if !f2.Test([]byte("one")) || !f2.Test([]byte("two")) {
+		t.Errorf("round-tripped filter should contain added elements")
+	}
+	if f2.Test([]byte("three")) {
+		t.Errorf("round-tripped filter should not contain []byte(\"three\")")
+	}
+}
+
+func TestUnmarshalBinaryInvalidMagic(t *testing.T) {
+	f1, _ := NewXXH3Filter(1000, 4)
+	data := f1.encode()
+	copy(data, "NOPE")
+
+	f2 := &Filter[[]byte]{}
+	if err := f2.UnmarshalBinary(data); err != ERR_INVALID_MAGIC {
+		t.Errorf("expected ERR_INVALID_MAGIC, got %v", err)
+	}
+}
+
+func TestWriteToReadFromSequential(t *testing.T) {
+	f1, _ := NewXXH3Filter(1000, 4)
+	f1.Add([]byte("one"))
+	f2, _ := NewXXH3Filter(500, 3)
+	f2.Add([]byte("two"))
+
+	var buf bytes.Buffer
+	f1.WriteTo(&buf)
+	f2.WriteTo(&buf)
+
+	g1 := &Filter[[]byte]{}
+	if _, err := g1.ReadFrom(&buf); err != nil {
+		t.Fatalf("first ReadFrom failed: %v", err)
+	}
+	g1.SetHashFunction128(xxh3Hash128)
+
+	g2 := &Filter[[]byte]{}
+	if _, err := g2.ReadFrom(&buf); err != nil {
+		t.Fatalf("second ReadFrom failed: %v", err)
+	}
+	g2.SetHashFunction128(xxh3Hash128)
+
+	if !g1.Test([]byte("one")) {
+		t.Errorf("first round-tripped filter should contain []byte(\"one\")")
+	}
+	if !g2.Test([]byte("two")) {
+		t.Errorf("second round-tripped filter should contain []byte(\"two\")")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no leftover bytes in buffer, got %v", buf.Len())
+	}
+}
+
+func TestUnmarshalBinaryShortBytes(t *testing.T) {
+	f1, _ := NewXXH3Filter(1000, 4)
+	data := f1.encode()
+	truncated := data[:len(data)-5]
+
+	f2 := &Filter[[]byte]{}
+	if err := f2.UnmarshalBinary(truncated); err != ERR_SHORT_BYTES {
+		t.Errorf("expected ERR_SHORT_BYTES, got %v", err)
+	}
+}
+
+func TestBinaryMarshalRoundTrip(t *testing.T) {
+	f1, _ := NewXXH3Filter(1000, 4)
+	f1.Add([]byte("one"))
+
+	data, err := f1.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	f2 := &Filter[[]byte]{}
+	if err := f2.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	f2.SetHashFunction128(xxh3Hash128)
+
+	if !f2.Test([]byte("one")) {
+		t.Errorf("round-tripped filter should contain []byte(\"one\")")
+	}
+}
+
+func TestJSONMarshalRoundTrip(t *testing.T) {
+	f1, _ := NewXXH3Filter(1000, 4)
+	f1.Add([]byte("one"))
+	f1.Add([]byte("two"))
+
+	data, err := json.Marshal(f1)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+
+	f2 := &Filter[[]byte]{}
+	if err := json.Unmarshal(data, f2); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v", err)
+	}
+	f2.SetHashFunction128(xxh3Hash128)
+
+	if f2.BitCount() != f1.BitCount() || f2.HashCount() != f1.HashCount() {
+		t.Errorf("round-tripped filter has different dimensions: got (%v, %v), want (%v, %v)", f2.BitCount(), f2.HashCount(), f1.BitCount(), f1.HashCount())
+	}
+	if !f2.Test([]byte("one")) || !f2.Test([]byte("two")) {
+		t.Errorf("round-tripped filter should contain added elements")
+	}
+	if f2.Test([]byte("three")) {
+		t.Errorf("round-tripped filter should not contain []byte(\"three\")")
+	}
+}
+
+func TestGobEncodeDecode(t *testing.T) {
+	f1, _ := NewXXH3Filter(1000, 4)
+	f1.Add([]byte("one"))
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(f1); err != nil {
+		t.Fatalf("gob encode failed: %v", err)
+	}
+
+	f2 := &Filter[[]byte]{}
+	if err := gob.NewDecoder(&buf).Decode(f2); err != nil {
+		t.Fatalf("gob decode failed: %v", err)
+	}
+	f2.SetHashFunction128(xxh3Hash128)
+
+	if !f2.Test([]byte("one")) {
+		t.Errorf("round-tripped filter should contain []byte(\"one\")")
+	}
+}
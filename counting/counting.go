@@ -0,0 +1,194 @@
+// Package counting provides a Bloom filter that supports Remove, at the cost of
+// replacing the single bit per index with a small saturating counter.
+package counting
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/fission-codes/go-bloom"
+	"github.com/zeebo/xxh3"
+)
+
+var ERR_INCOMPATIBLE_HASH_FUNCTIONS = errors.New("Incompatible Hash Functions")
+var ERR_INCOMPATIBLE_HASH_COUNT = errors.New("Incompatible Hash Count")
+var ERR_INCOMPATIBLE_BIT_COUNT = errors.New("Incompatible Bit Count")
+
+// maxCounter is the largest value a counter can hold. Counters are packed two to a
+// byte as 4-bit nibbles, so a counter saturates at 15 rather than overflowing and
+// wrapping back around to 0, which would otherwise cause a false negative on Test.
+const maxCounter = 15
+
+// CountingFilter is a Bloom filter where each indexed position is a saturating 4-bit
+// counter instead of a single bit, so Add can be undone with Remove.
+type CountingFilter[T any] struct {
+	bitCount  uint64 // filter size in counters
+	hashCount uint64 // number of hash functions
+	counters  []byte // nibble-packed counters, two per byte
+	function  bloom.HashFunction[T]
+}
+
+// NewCountingFilter returns a new CountingFilter with the specified number of
+// counters and hash functions.
+// bitCount and hashCount will be set to 1 if a number less than 1 is provided, to
+// avoid panic.
+func NewCountingFilter[T any](bitCount, hashCount uint64, function bloom.HashFunction[T]) *CountingFilter[T] {
+	safeBitCount := max(1, bitCount)
+	safeHashCount := max(1, hashCount)
+	return &CountingFilter[T]{
+		bitCount:  safeBitCount,
+		hashCount: safeHashCount,
+		counters:  make([]byte, (safeBitCount+1)/2),
+		function:  function,
+	}
+}
+
+func NewXXH3CountingFilter(bitCount, hashCount uint64) *CountingFilter[[]byte] {
+	var function bloom.HashFunction[[]byte] = xxh3.HashSeed
+	return NewCountingFilter(bitCount, hashCount, function)
+}
+
+// NewCountingFilterWithEstimates returns a new CountingFilter with estimated
+// parameters based on the specified number of elements and false positive
+// probability rate.
+func NewCountingFilterWithEstimates[T any](n uint64, fpp float64, function bloom.HashFunction[T]) *CountingFilter[T] {
+	m, k := bloom.EstimateParameters(n, fpp)
+	return NewCountingFilter(m, k, function)
+}
+
+func NewXXH3CountingFilterWithEstimates(n uint64, fpp float64) *CountingFilter[[]byte] {
+	var function bloom.HashFunction[[]byte] = xxh3.HashSeed
+	return NewCountingFilterWithEstimates(n, fpp, function)
+}
+
+// BitCount returns the filter size in counters.
+func (f *CountingFilter[T]) BitCount() uint64 {
+	return f.bitCount
+}
+
+// HashCount returns the number of hash functions.
+func (f *CountingFilter[T]) HashCount() uint64 {
+	return f.hashCount
+}
+
+// counter returns the value of the counter at index.
+func (f *CountingFilter[T]) counter(index uint64) byte {
+	b := f.counters[index/2]
+	if index%2 == 0 {
+		return b & 0x0f
+	}
+	return b >> 4
+}
+
+// setCounter sets the counter at index to value, which must be in [0, maxCounter].
+func (f *CountingFilter[T]) setCounter(index uint64, value byte) {
+	b := f.counters[index/2]
+	if index%2 == 0 {
+		f.counters[index/2] = (b & 0xf0) | value
+	} else {
+		f.counters[index/2] = (b & 0x0f) | (value << 4)
+	}
+}
+
+// hasher returns a Hasher configured the same way Add/Test/Remove all use, so the
+// same HashFunction[T] seed sequence indexes into the counters.
+func (f *CountingFilter[T]) hasher() *bloom.Hasher[T, bloom.HashFunction[T]] {
+	return bloom.NewHasher[T](f.bitCount, f.hashCount, f.function)
+}
+
+// Add increments each of the hashCount counters for the specified data, saturating
+// at maxCounter rather than overflowing.
+func (f *CountingFilter[T]) Add(data T) *CountingFilter[T] {
+	hasher := f.hasher()
+	for hasher.Next() {
+		index := hasher.Value(data)
+		if c := f.counter(index); c < maxCounter {
+			f.setCounter(index, c+1)
+		}
+	}
+	return f
+}
+
+// Test returns true if all hashCount counters for the specified data are non-zero.
+func (f *CountingFilter[T]) Test(data T) bool {
+	hasher := f.hasher()
+	for hasher.Next() {
+		if f.counter(hasher.Value(data)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Remove decrements each of the hashCount counters for the specified data. A counter
+// already at 0 is left at 0. A counter already at maxCounter is left untouched,
+// since it is saturated and may represent more additions than it can count -
+// decrementing it could produce a false negative for an item that is still present.
+func (f *CountingFilter[T]) Remove(data T) *CountingFilter[T] {
+	hasher := f.hasher()
+	for hasher.Next() {
+		index := hasher.Value(data)
+		if c := f.counter(index); c > 0 && c < maxCounter {
+			f.setCounter(index, c-1)
+		}
+	}
+	return f
+}
+
+// EstimateEntries estimates the number of entries in the filter, derived from the
+// sum of counters divided by hashCount.
+func (f *CountingFilter[T]) EstimateEntries() uint64 {
+	var sum uint64
+	for i := uint64(0); i < f.bitCount; i++ {
+		sum += uint64(f.counter(i))
+	}
+	return sum / f.hashCount
+}
+
+func (f *CountingFilter[T]) checkCompatibility(other *CountingFilter[T]) error {
+	if reflect.ValueOf(f.function).Pointer() != reflect.ValueOf(other.function).Pointer() {
+		return ERR_INCOMPATIBLE_HASH_FUNCTIONS
+	}
+	if f.hashCount != other.hashCount {
+		return ERR_INCOMPATIBLE_HASH_COUNT
+	}
+	if f.bitCount != other.bitCount {
+		return ERR_INCOMPATIBLE_BIT_COUNT
+	}
+	return nil
+}
+
+// Union sets each of this filter's counters to the element-wise max of this
+// filter's and the other filter's counters.
+func (f *CountingFilter[T]) Union(other *CountingFilter[T]) error {
+	if err := f.checkCompatibility(other); err != nil {
+		return err
+	}
+	for i := uint64(0); i < f.bitCount; i++ {
+		if o := other.counter(i); o > f.counter(i) {
+			f.setCounter(i, o)
+		}
+	}
+	return nil
+}
+
+// Intersect sets each of this filter's counters to the element-wise min of this
+// filter's and the other filter's counters.
+func (f *CountingFilter[T]) Intersect(other *CountingFilter[T]) error {
+	if err := f.checkCompatibility(other); err != nil {
+		return err
+	}
+	for i := uint64(0); i < f.bitCount; i++ {
+		if o := other.counter(i); o < f.counter(i) {
+			f.setCounter(i, o)
+		}
+	}
+	return nil
+}
+
+func max(x, y uint64) uint64 {
+	if x > y {
+		return x
+	}
+	return y
+}
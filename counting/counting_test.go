@@ -0,0 +1,123 @@
+package counting
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestAddTestRemove(t *testing.T) {
+	f := NewXXH3CountingFilter(1000, 4)
+
+	n1 := []byte("one")
+	n2 := []byte("two")
+	f.Add(n1)
+
+	if !f.Test(n1) {
+		t.Errorf("%v should be in.", n1)
+	}
+	if f.Test(n2) {
+		t.Errorf("%v should not be in.", n2)
+	}
+
+	f.Remove(n1)
+	if f.Test(n1) {
+		t.Errorf("%v should not be in after Remove.", n1)
+	}
+}
+
+func TestRemoveNeverGoesBelowZero(t *testing.T) {
+	f := NewXXH3CountingFilter(1000, 4)
+	n1 := []byte("one")
+
+	f.Remove(n1)
+	f.Remove(n1)
+	if f.Test(n1) {
+		t.Errorf("%v should not be in a filter it was never added to.", n1)
+	}
+}
+
+func TestAddRemoveSharedIndex(t *testing.T) {
+	f := NewXXH3CountingFilter(1000, 4)
+	n1 := []byte("one")
+	n2 := []byte("two")
+
+	f.Add(n1)
+	f.Add(n2)
+	f.Remove(n1)
+
+	if f.Test(n1) {
+		t.Errorf("%v should not be in after Remove.", n1)
+	}
+	if !f.Test(n2) {
+		t.Errorf("%v should still be in, Remove(n1) should not have removed counters shared with n2.", n2)
+	}
+}
+
+func TestEstimateEntries(t *testing.T) {
+	f := NewXXH3CountingFilterWithEstimates(1000, 0.001)
+	for i := uint32(0); i < 1000; i++ {
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, i)
+		f.Add(b)
+	}
+
+	estimate := float64(f.EstimateEntries())
+	if estimate < 800 || estimate > 1200 {
+		t.Errorf("estimate out of range: %v", estimate)
+	}
+}
+
+func TestUnion(t *testing.T) {
+	f1 := NewXXH3CountingFilterWithEstimates(20, 0.01)
+	f1.Add([]byte{1})
+	f2 := NewXXH3CountingFilterWithEstimates(20, 0.01)
+	f2.Add([]byte{2})
+
+	if err := f1.Union(f2); err != nil {
+		t.Errorf("should not return an error")
+	}
+	if !f1.Test([]byte{1}) {
+		t.Errorf("should contain []byte{1}")
+	}
+	if !f1.Test([]byte{2}) {
+		t.Errorf("should contain []byte{2}")
+	}
+}
+
+func TestIntersect(t *testing.T) {
+	f1 := NewXXH3CountingFilterWithEstimates(20, 0.01)
+	f1.Add([]byte{1})
+	f1.Add([]byte{2})
+	f2 := NewXXH3CountingFilterWithEstimates(20, 0.01)
+	f2.Add([]byte{2})
+	f2.Add([]byte{3})
+
+	if err := f1.Intersect(f2); err != nil {
+		t.Errorf("should not return an error")
+	}
+	if f1.Test([]byte{1}) {
+		t.Errorf("should not contain []byte{1}")
+	}
+	if !f1.Test([]byte{2}) {
+		t.Errorf("should contain []byte{2}")
+	}
+	if f1.Test([]byte{3}) {
+		t.Errorf("should not contain []byte{3}")
+	}
+}
+
+func TestIncompatibleBitCountUnion(t *testing.T) {
+	f1 := NewXXH3CountingFilter(128, 3)
+	f2 := NewXXH3CountingFilter(64, 3)
+	if err := f1.Union(f2); err != ERR_INCOMPATIBLE_BIT_COUNT {
+		t.Errorf("should return ERR_INCOMPATIBLE_BIT_COUNT")
+	}
+}
+
+func TestIncompatibleHashCountUnion(t *testing.T) {
+	f1 := NewXXH3CountingFilter(128, 3)
+	f2 := NewXXH3CountingFilter(128, 2)
+	if err := f1.Union(f2); err != ERR_INCOMPATIBLE_HASH_COUNT {
+		t.Errorf("should return ERR_INCOMPATIBLE_HASH_COUNT")
+	}
+}
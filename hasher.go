@@ -6,6 +6,13 @@ import (
 
 type HashFunction[T any] func(T, uint64) uint64
 
+// HashFunction128 returns a 128-bit hash of data, as two independent 64-bit halves,
+// for use with the Kirsch-Mitzenmacher double-hashing scheme in Hasher128. Producing
+// both halves from a single pass over data (as xxh3.Hash128Seed does) avoids the
+// per-index function call and rejection-sampling loop that HashFunction otherwise
+// needs.
+type HashFunction128[T any] func(T, uint64) (uint64, uint64)
+
 // Hasher generates hashCount hashes as bit indices for the Bloom filter.
 type Hasher[T any, H HashFunction[T]] struct {
 	bitCount  uint64 // number of bits we need to index into
@@ -72,3 +79,50 @@ func (h *Hasher[T, H]) Value(data T) uint64 {
 func bitmask(bitCount uint64) uint64 {
 	return NextPowerOfTwo(bitCount) - 1
 }
+
+// Hasher128 generates hashCount bit indices from a single 128-bit hash using the
+// Kirsch-Mitzenmacher double-hashing scheme: index_i = (h1 + i*h2) mod bitCount for
+// i = 0..hashCount-1. Unlike Hasher, this calls the hash function exactly once per
+// Add/Test and never needs to reject an out-of-bounds index.
+type Hasher128[T any] struct {
+	bitCount   uint64 // number of bits we need to index into
+	hashCount  uint64 // number of indices to derive from the one 128-bit hash
+	count      uint64 // number of indices generated so far
+	h1, h2     uint64 // the two halves of the 128-bit hash, computed on first use
+	powerOfTwo bool   // true if bitCount is a power of two, allowing a bitmask instead of a modulo
+	bitmask    uint64
+	function   HashFunction128[T]
+}
+
+// NewHasher128 returns a new Hasher128.
+func NewHasher128[T any](bitCount, hashCount uint64, function HashFunction128[T]) *Hasher128[T] {
+	return &Hasher128[T]{
+		bitCount:   bitCount,
+		hashCount:  hashCount,
+		count:      0,
+		powerOfTwo: bitCount == NextPowerOfTwo(bitCount),
+		bitmask:    bitCount - 1,
+		function:   function,
+	}
+}
+
+// Next returns true if the Hasher128 has more indices to generate.
+func (h *Hasher128[T]) Next() bool {
+	return h.count < h.hashCount
+}
+
+// Value returns the next index from the Hasher128. The 128-bit hash is computed
+// once, on the first call, and reused to derive every subsequent index.
+func (h *Hasher128[T]) Value(data T) uint64 {
+	if h.count == 0 {
+		h.h1, h.h2 = h.function(data, 0)
+	}
+
+	index := h.h1 + h.count*h.h2
+	h.count += 1
+
+	if h.powerOfTwo {
+		return index & h.bitmask
+	}
+	return index % h.bitCount
+}
@@ -0,0 +1,133 @@
+// Package rolling provides a bounded-memory Bloom filter for unbounded streams,
+// inspired by the lifecycle of Ethereum's fast-sync bloom filter.
+package rolling
+
+import (
+	"sync"
+
+	"github.com/fission-codes/go-bloom"
+)
+
+// DefaultCeiling is a fill ratio ceiling suitable for passing to
+// NewRollingFilter/NewXXH3Rolling, which do not substitute it for an unset ceiling:
+// once more than half of a generation's bits are set, its actual false positive
+// probability has badly outgrown its design target.
+const DefaultCeiling = 0.5
+
+// RollingFilter wraps a *bloom.Filter[T] sized for a fixed maximum bit budget.
+// Once the active generation's fill ratio exceeds a soft ceiling, it is atomically
+// replaced with a freshly-allocated, zeroed generation of the same size, and the old
+// generation is handed to an optional OnRotate callback for archival or
+// asynchronous merging into a secondary structure. This bounds memory growth for
+// streams whose size is not known up front - e.g. deduping trie-node hashes during a
+// long sync - at the cost of eventually forgetting items added long enough ago.
+//
+// For one generation after a rotation, Test also consults the previous generation,
+// so items added just before a rotation are not immediately forgotten.
+type RollingFilter[T any] struct {
+	mu        sync.RWMutex
+	active    *bloom.Filter[T]
+	previous  *bloom.Filter[T] // nil until the first rotation
+	ceiling   float64
+	newFilter func() (*bloom.Filter[T], error)
+	onRotate  func(old *bloom.Filter[T])
+}
+
+// NewRollingFilter returns a new RollingFilter whose generations are each sized with
+// the specified number of bits and hash functions. ceiling is the fill ratio, in
+// (0, 1], past which a generation is rotated out; DefaultCeiling is a reasonable
+// choice.
+func NewRollingFilter[T any](bitCount, hashCount uint64, ceiling float64, function bloom.HashFunction[T]) (*RollingFilter[T], error) {
+	newFilter := func() (*bloom.Filter[T], error) {
+		return bloom.NewFilter(bitCount, hashCount, function)
+	}
+
+	active, err := newFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	return &RollingFilter[T]{
+		active:    active,
+		ceiling:   ceiling,
+		newFilter: newFilter,
+	}, nil
+}
+
+// NewXXH3Rolling returns a new RollingFilter using the Kirsch-Mitzenmacher fast path
+// (see bloom.NewFilter128), seeded by xxh3.Hash128Seed.
+func NewXXH3Rolling(bitCount, hashCount uint64, ceiling float64) (*RollingFilter[[]byte], error) {
+	newFilter := func() (*bloom.Filter[[]byte], error) {
+		return bloom.NewXXH3Filter(bitCount, hashCount)
+	}
+
+	active, err := newFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	return &RollingFilter[[]byte]{
+		active:    active,
+		ceiling:   ceiling,
+		newFilter: newFilter,
+	}, nil
+}
+
+// OnRotate registers callback to be invoked, with the outgoing generation, every
+// time Add triggers a rotation. Only one callback may be registered at a time; a
+// later call replaces the earlier one.
+func (r *RollingFilter[T]) OnRotate(callback func(old *bloom.Filter[T])) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onRotate = callback
+}
+
+// Add sets the active generation's bits for data, then rotates to a fresh generation
+// if the active generation's fill ratio now exceeds the ceiling.
+func (r *RollingFilter[T]) Add(data T) *RollingFilter[T] {
+	old, callback := r.addAndMaybeRotate(data)
+	if old != nil && callback != nil {
+		callback(old)
+	}
+	return r
+}
+
+// addAndMaybeRotate does the locked work of Add, returning the rotated-out
+// generation and the callback to invoke for it, if a rotation happened. The callback
+// is invoked by the caller after the lock is released, so it may safely call back
+// into the RollingFilter (e.g. to Test the outgoing generation).
+func (r *RollingFilter[T]) addAndMaybeRotate(data T) (*bloom.Filter[T], func(*bloom.Filter[T])) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.active.Add(data)
+	if r.active.FillRatio() <= r.ceiling {
+		return nil, nil
+	}
+
+	next, err := r.newFilter()
+	if err != nil {
+		// Allocation failed; keep using the over-full active generation rather than
+		// losing it, and try rotating again on a later Add.
+		return nil, nil
+	}
+
+	old := r.active
+	r.previous = old
+	r.active = next
+	return old, r.onRotate
+}
+
+// Test returns true if the active generation contains data, or if the previous
+// generation does. The previous generation is only consulted during the grace
+// window between two rotations - once a second rotation happens, the generation
+// before that is dropped for good.
+func (r *RollingFilter[T]) Test(data T) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.active.Test(data) {
+		return true
+	}
+	return r.previous != nil && r.previous.Test(data)
+}
@@ -0,0 +1,82 @@
+package rolling
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/fission-codes/go-bloom"
+)
+
+func item(i uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, i)
+	return b
+}
+
+func TestAddTest(t *testing.T) {
+	r, _ := NewXXH3Rolling(1000, 4, DefaultCeiling)
+
+	r.Add([]byte("one"))
+	if !r.Test([]byte("one")) {
+		t.Errorf("should contain []byte(\"one\")")
+	}
+	if r.Test([]byte("two")) {
+		t.Errorf("should not contain []byte(\"two\")")
+	}
+}
+
+func TestRotatesPastCeiling(t *testing.T) {
+	r, _ := NewXXH3Rolling(1024, 4, DefaultCeiling)
+
+	rotated := false
+	r.OnRotate(func(old *bloom.Filter[[]byte]) {
+		rotated = true
+		if old.FillRatio() <= DefaultCeiling {
+			t.Errorf("rotated-out generation should have exceeded the ceiling, got %v", old.FillRatio())
+		}
+	})
+
+	for i := uint32(0); i < 2000; i++ {
+		r.Add(item(i))
+	}
+
+	if !rotated {
+		t.Errorf("expected at least one rotation after adding many entries")
+	}
+	if r.active.FillRatio() > DefaultCeiling {
+		t.Errorf("active generation should be below the ceiling after rotating, got %v", r.active.FillRatio())
+	}
+}
+
+func TestGraceWindowAfterRotation(t *testing.T) {
+	r, _ := NewXXH3Rolling(1024, 4, DefaultCeiling)
+
+	r.Add([]byte("first"))
+
+	rotations := 0
+	r.OnRotate(func(old *bloom.Filter[[]byte]) { rotations++ })
+
+	// Add just enough distinct items to trigger exactly one rotation.
+	for i := uint32(0); rotations == 0; i++ {
+		r.Add(item(i))
+	}
+
+	if !r.Test([]byte("first")) {
+		t.Errorf("item added before a rotation should still be found during the grace window")
+	}
+}
+
+func TestForgetsAfterSecondRotation(t *testing.T) {
+	r, _ := NewXXH3Rolling(512, 4, DefaultCeiling)
+
+	r.Add([]byte("first"))
+
+	// Force at least two rotations.
+	for i := uint32(0); i < 4000; i++ {
+		r.Add(item(i))
+	}
+
+	if r.Test([]byte("first")) {
+		t.Errorf("item added two rotations ago should no longer be found")
+	}
+}
@@ -0,0 +1,208 @@
+// Package scalable provides a Bloom filter that grows automatically as items are
+// added, so callers do not need to know the number of elements n up front the way
+// bloom.NewFilterWithEstimates requires.
+package scalable
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/fission-codes/go-bloom"
+)
+
+var ERR_INVALID_MAGIC = errors.New("invalid Scalable filter magic bytes")
+var ERR_UNSUPPORTED_VERSION = errors.New("unsupported Scalable filter wire format version")
+
+// scalableMagic identifies the start of a serialized ScalableFilter on the wire.
+var scalableMagic = [4]byte{'S', 'B', 'L', 'M'}
+
+// scalableVersion is the wire format version written by WriteTo and expected by
+// ReadFrom.
+const scalableVersion uint8 = 1
+
+// ScalableFilter is a Bloom filter composed of a slice of layers, each a
+// *bloom.Filter[T], with geometrically growing capacity and tightening false
+// positive probability, following the Almeida et al. Scalable Bloom Filter design.
+// Test consults every layer; Add only ever writes to the most recently added layer,
+// allocating a new one once the active layer's estimated fill reaches its capacity.
+type ScalableFilter[T any] struct {
+	layers     []*bloom.Filter[T]
+	fpp        float64 // fpp to use for the next layer allocated
+	growth     uint64
+	tightening float64
+	function   bloom.HashFunction[T]
+}
+
+// NewScalable returns a new ScalableFilter with an initial layer sized for n elements
+// at the given false positive probability. Each time the active layer fills up, a new
+// layer is allocated with its capacity multiplied by growth (typically 2 or 4) and its
+// fpp multiplied by tightening (typically 0.8 or 0.9), so the compounded false
+// positive probability across all layers is bounded by fpp / (1 - tightening).
+func NewScalable[T any](n uint64, fpp float64, growth uint64, tightening float64, function bloom.HashFunction[T]) (*ScalableFilter[T], error) {
+	layer, err := bloom.NewFilterWithEstimates(n, fpp, function)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScalableFilter[T]{
+		layers:     []*bloom.Filter[T]{layer},
+		fpp:        fpp * tightening,
+		growth:     growth,
+		tightening: tightening,
+		function:   function,
+	}, nil
+}
+
+// activeLayer returns the layer that Add should write to.
+func (s *ScalableFilter[T]) activeLayer() *bloom.Filter[T] {
+	return s.layers[len(s.layers)-1]
+}
+
+// addLayer allocates and appends a new, larger, tighter-fpp layer and returns it.
+func (s *ScalableFilter[T]) addLayer() (*bloom.Filter[T], error) {
+	active := s.activeLayer()
+	n := active.EstimateCapacity() * s.growth
+
+	layer, err := bloom.NewFilterWithEstimates(n, s.fpp, s.function)
+	if err != nil {
+		return nil, err
+	}
+
+	s.layers = append(s.layers, layer)
+	s.fpp *= s.tightening
+	return layer, nil
+}
+
+// Add sets hashCount bits in the active layer for the specified data, first
+// allocating a new layer if the active layer's estimated fill has reached its
+// capacity.
+func (s *ScalableFilter[T]) Add(data T) *ScalableFilter[T] {
+	active := s.activeLayer()
+	if active.EstimateEntries() >= active.EstimateCapacity() {
+		// If allocation fails, keep writing to the full active layer rather than
+		// dropping data.
+		if next, err := s.addLayer(); err == nil {
+			active = next
+		}
+	}
+
+	active.Add(data)
+	return s
+}
+
+// Test returns true if any layer contains the specified data.
+func (s *ScalableFilter[T]) Test(data T) bool {
+	for _, layer := range s.layers {
+		if layer.Test(data) {
+			return true
+		}
+	}
+	return false
+}
+
+// EstimateEntries returns the sum of each layer's estimated number of entries.
+func (s *ScalableFilter[T]) EstimateEntries() uint64 {
+	var total uint64
+	for _, layer := range s.layers {
+		total += layer.EstimateEntries()
+	}
+	return total
+}
+
+// LayerCount returns the number of layers currently making up the filter.
+func (s *ScalableFilter[T]) LayerCount() int {
+	return len(s.layers)
+}
+
+// SetHashFunction attaches function to the filter and every one of its layers. The
+// HashFunction cannot be part of the wire format produced by WriteTo, so callers
+// decoding a filter with ReadFrom must call SetHashFunction before using Add or Test.
+func (s *ScalableFilter[T]) SetHashFunction(function bloom.HashFunction[T]) *ScalableFilter[T] {
+	s.function = function
+	for _, layer := range s.layers {
+		layer.SetHashFunction(function)
+	}
+	return s
+}
+
+// WriteTo writes the filter to w as a magic/version header, the growth and
+// tightening parameters, the fpp to be used for the next layer, and each layer in
+// turn using bloom.Filter[T].WriteTo, so a growing filter can be persisted and later
+// resumed. It satisfies io.WriterTo.
+func (s *ScalableFilter[T]) WriteTo(w io.Writer) (int64, error) {
+	var header bytes.Buffer
+	header.Write(scalableMagic[:])
+	header.WriteByte(scalableVersion)
+	binary.Write(&header, binary.BigEndian, uint64(len(s.layers)))
+	binary.Write(&header, binary.BigEndian, s.growth)
+	binary.Write(&header, binary.BigEndian, math.Float64bits(s.tightening))
+	binary.Write(&header, binary.BigEndian, math.Float64bits(s.fpp))
+
+	n, err := w.Write(header.Bytes())
+	total := int64(n)
+	if err != nil {
+		return total, err
+	}
+
+	for _, layer := range s.layers {
+		m, err := layer.WriteTo(w)
+		total += m
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+// ReadFrom reads a filter previously written by WriteTo, replacing this filter's
+// layers, growth, and tightening parameters. The HashFunction cannot be serialized,
+// so callers must call SetHashFunction afterward before using Add or Test. It
+// satisfies io.ReaderFrom.
+func (s *ScalableFilter[T]) ReadFrom(r io.Reader) (int64, error) {
+	var header [4 + 1 + 8 + 8 + 8 + 8]byte
+	n, err := io.ReadFull(r, header[:])
+	total := int64(n)
+	if err != nil {
+		return total, err
+	}
+
+	if !bytes.Equal(header[:4], scalableMagic[:]) {
+		return total, ERR_INVALID_MAGIC
+	}
+	offset := 4
+
+	version := header[offset]
+	offset++
+	if version != scalableVersion {
+		return total, ERR_UNSUPPORTED_VERSION
+	}
+
+	layerCount := binary.BigEndian.Uint64(header[offset : offset+8])
+	offset += 8
+	growth := binary.BigEndian.Uint64(header[offset : offset+8])
+	offset += 8
+	tightening := math.Float64frombits(binary.BigEndian.Uint64(header[offset : offset+8]))
+	offset += 8
+	fpp := math.Float64frombits(binary.BigEndian.Uint64(header[offset : offset+8]))
+
+	layers := make([]*bloom.Filter[T], 0, layerCount)
+	for i := uint64(0); i < layerCount; i++ {
+		layer := &bloom.Filter[T]{}
+		m, err := layer.ReadFrom(r)
+		total += m
+		if err != nil {
+			return total, err
+		}
+		layers = append(layers, layer)
+	}
+
+	s.layers = layers
+	s.growth = growth
+	s.tightening = tightening
+	s.fpp = fpp
+	return total, nil
+}
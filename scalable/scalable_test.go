@@ -0,0 +1,78 @@
+package scalable
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/fission-codes/go-bloom"
+	"github.com/zeebo/xxh3"
+)
+
+var XXH3 bloom.HashFunction[[]byte] = xxh3.HashSeed
+
+func item(i uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, i)
+	return b
+}
+
+func TestGrowsAcrossLayers(t *testing.T) {
+	f, err := NewScalable(10, 0.01, 2, 0.9, XXH3)
+	if err != nil {
+		t.Fatalf("NewScalable failed: %v", err)
+	}
+
+	for i := uint32(0); i < 1000; i++ {
+		f.Add(item(i))
+	}
+
+	if f.LayerCount() <= 1 {
+		t.Errorf("expected more than one layer after adding many entries, got %v", f.LayerCount())
+	}
+
+	for i := uint32(0); i < 1000; i++ {
+		if !f.Test(item(i)) {
+			t.Errorf("%v should be in the filter", i)
+		}
+	}
+}
+
+func TestEstimateEntries(t *testing.T) {
+	f, _ := NewScalable(10, 0.01, 2, 0.9, XXH3)
+	for i := uint32(0); i < 500; i++ {
+		f.Add(item(i))
+	}
+
+	estimate := float64(f.EstimateEntries())
+	if estimate < 400 || estimate > 600 {
+		t.Errorf("estimate out of range: %v", estimate)
+	}
+}
+
+func TestWriteToReadFrom(t *testing.T) {
+	f1, _ := NewScalable(10, 0.01, 2, 0.9, XXH3)
+	for i := uint32(0); i < 500; i++ {
+		f1.Add(item(i))
+	}
+
+	var buf bytes.Buffer
+	if _, err := f1.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+
+	f2 := &ScalableFilter[[]byte]{}
+	if _, err := f2.ReadFrom(&buf); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	f2.SetHashFunction(XXH3)
+
+	if f2.LayerCount() != f1.LayerCount() {
+		t.Errorf("expected %v layers, got %v", f1.LayerCount(), f2.LayerCount())
+	}
+	for i := uint32(0); i < 500; i++ {
+		if !f2.Test(item(i)) {
+			t.Errorf("%v should be in the round-tripped filter", i)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Package sync provides a thread-safe wrapper around a *bloom.Filter[T].
+package sync
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/fission-codes/go-bloom"
+)
+
+// SyncFilter wraps a *bloom.Filter[T] and makes concurrent Add, Test, Union,
+// Intersect, and Bytes calls safe. Rather than a single sync.RWMutex guarding the
+// whole filter, the underlying bitset is divided into stripes, and Add/Test only
+// lock the stripe(s) containing the bits touched by the item being added or tested -
+// so two goroutines working on unrelated items rarely contend with each other. A
+// separate global lock gates bulk operations (Union, Intersect, Bytes, Snapshot)
+// against every Add/Test, and against each other.
+type SyncFilter[T any] struct {
+	filter  *bloom.Filter[T]
+	global  sync.RWMutex
+	stripes []sync.RWMutex
+}
+
+// NewSyncFilter returns a new SyncFilter wrapping f, with its bitset divided into
+// stripes stripes. stripes will be set to runtime.GOMAXPROCS(0) if a number less than
+// 1 is provided.
+func NewSyncFilter[T any](f *bloom.Filter[T], stripes int) *SyncFilter[T] {
+	if stripes < 1 {
+		stripes = runtime.GOMAXPROCS(0)
+	}
+	return &SyncFilter[T]{
+		filter:  f,
+		stripes: make([]sync.RWMutex, stripes),
+	}
+}
+
+// stripesFor returns the sorted, de-duplicated stripe indices that the filter's
+// hashCount bits for data fall into, using the same HashFunction/HashFunction128
+// and seed sequence that the wrapped filter's own Add/Test use.
+//
+// The underlying bitset packs 8 bits per byte and sets/tests a bit with a
+// byte-granularity read-modify-write, so two bit indices sharing a byte must always
+// map to the same stripe - otherwise a writer holding one stripe's lock could race
+// with a writer or reader holding another stripe's lock over the same byte. Striping
+// by byte index (bitIndex / 8) rather than by raw bit index guarantees that.
+func (s *SyncFilter[T]) stripesFor(data T) []int {
+	touched := make(map[int]struct{}, s.filter.HashCount())
+	stripeCount := uint64(len(s.stripes))
+
+	if function128 := s.filter.HashFunction128(); function128 != nil {
+		hasher := bloom.NewHasher128[T](s.filter.BitCount(), s.filter.HashCount(), function128)
+		for hasher.Next() {
+			touched[int((hasher.Value(data)/8)%stripeCount)] = struct{}{}
+		}
+	} else {
+		hasher := bloom.NewHasher[T](s.filter.BitCount(), s.filter.HashCount(), s.filter.HashFunction())
+		for hasher.Next() {
+			touched[int((hasher.Value(data)/8)%stripeCount)] = struct{}{}
+		}
+	}
+
+	indices := make([]int, 0, len(touched))
+	for stripe := range touched {
+		indices = append(indices, stripe)
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+// Add sets the filter's bits for data, locking only the stripe(s) they fall into.
+func (s *SyncFilter[T]) Add(data T) *SyncFilter[T] {
+	s.global.RLock()
+	defer s.global.RUnlock()
+
+	for _, stripe := range s.stripesFor(data) {
+		s.stripes[stripe].Lock()
+		defer s.stripes[stripe].Unlock()
+	}
+
+	s.filter.Add(data)
+	return s
+}
+
+// Test returns true if all of the filter's bits for data are set, locking only the
+// stripe(s) they fall into.
+func (s *SyncFilter[T]) Test(data T) bool {
+	s.global.RLock()
+	defer s.global.RUnlock()
+
+	for _, stripe := range s.stripesFor(data) {
+		s.stripes[stripe].RLock()
+		defer s.stripes[stripe].RUnlock()
+	}
+
+	return s.filter.Test(data)
+}
+
+// Union sets this filter's bitset to the union of other's bitset, excluding all
+// concurrent Add/Test calls on both filters for the duration.
+func (s *SyncFilter[T]) Union(other *SyncFilter[T]) error {
+	s.global.Lock()
+	defer s.global.Unlock()
+	other.global.Lock()
+	defer other.global.Unlock()
+
+	return s.filter.Union(other.filter)
+}
+
+// Intersect sets this filter's bitset to the intersection of other's bitset,
+// excluding all concurrent Add/Test calls on both filters for the duration.
+func (s *SyncFilter[T]) Intersect(other *SyncFilter[T]) error {
+	s.global.Lock()
+	defer s.global.Unlock()
+	other.global.Lock()
+	defer other.global.Unlock()
+
+	return s.filter.Intersect(other.filter)
+}
+
+// Bytes returns the Bloom binary as a byte slice, excluding all concurrent Add calls
+// for the duration.
+func (s *SyncFilter[T]) Bytes() []byte {
+	s.global.RLock()
+	defer s.global.RUnlock()
+	for i := range s.stripes {
+		s.stripes[i].RLock()
+		defer s.stripes[i].RUnlock()
+	}
+
+	return s.filter.Bytes()
+}
+
+// Snapshot returns a consistent copy of the wrapped filter, excluding all concurrent
+// Add/Test/Union/Intersect/Bytes calls for the duration.
+func (s *SyncFilter[T]) Snapshot() *bloom.Filter[T] {
+	s.global.Lock()
+	defer s.global.Unlock()
+
+	return s.filter.Copy()
+}
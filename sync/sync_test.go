@@ -0,0 +1,132 @@
+package sync
+
+import (
+	"encoding/binary"
+	"sync"
+	"testing"
+
+	"github.com/fission-codes/go-bloom"
+)
+
+func item(i uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, i)
+	return b
+}
+
+func TestAddTest(t *testing.T) {
+	f, _ := bloom.NewXXH3Filter(1000, 4)
+	sf := NewSyncFilter(f, 4)
+
+	sf.Add([]byte("one"))
+	if !sf.Test([]byte("one")) {
+		t.Errorf("should contain []byte(\"one\")")
+	}
+	if sf.Test([]byte("two")) {
+		t.Errorf("should not contain []byte(\"two\")")
+	}
+}
+
+func TestConcurrentAddTest(t *testing.T) {
+	f, _ := bloom.NewXXH3Filter(10000, 4)
+	sf := NewSyncFilter(f, 8)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := uint32(0); i < 100; i++ {
+				sf.Add(item(uint32(g)*100 + i))
+				sf.Test(item(uint32(g)*100 + i))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	for i := uint32(0); i < 1600; i++ {
+		if !sf.Test(item(i)) {
+			t.Errorf("%v should be in the filter", i)
+		}
+	}
+}
+
+func TestUnion(t *testing.T) {
+	f1, _ := bloom.NewXXH3FilterWithEstimates(20, 0.01)
+	sf1 := NewSyncFilter(f1, 4)
+	sf1.Add([]byte{1})
+
+	f2, _ := bloom.NewXXH3FilterWithEstimates(20, 0.01)
+	sf2 := NewSyncFilter(f2, 4)
+	sf2.Add([]byte{2})
+
+	if err := sf1.Union(sf2); err != nil {
+		t.Errorf("should not return an error")
+	}
+	if !sf1.Test([]byte{1}) {
+		t.Errorf("should contain []byte{1}")
+	}
+	if !sf1.Test([]byte{2}) {
+		t.Errorf("should contain []byte{2}")
+	}
+}
+
+func TestIntersect(t *testing.T) {
+	f1, _ := bloom.NewXXH3FilterWithEstimates(20, 0.01)
+	sf1 := NewSyncFilter(f1, 4)
+	sf1.Add([]byte{1})
+	sf1.Add([]byte{2})
+
+	f2, _ := bloom.NewXXH3FilterWithEstimates(20, 0.01)
+	sf2 := NewSyncFilter(f2, 4)
+	sf2.Add([]byte{2})
+	sf2.Add([]byte{3})
+
+	if err := sf1.Intersect(sf2); err != nil {
+		t.Errorf("should not return an error")
+	}
+	if sf1.Test([]byte{1}) {
+		t.Errorf("should not contain []byte{1}")
+	}
+	if !sf1.Test([]byte{2}) {
+		t.Errorf("should contain []byte{2}")
+	}
+	if sf1.Test([]byte{3}) {
+		t.Errorf("should not contain []byte{3}")
+	}
+}
+
+func TestBytes(t *testing.T) {
+	f, _ := bloom.NewXXH3Filter(1000, 4)
+	sf := NewSyncFilter(f, 4)
+	sf.Add([]byte("one"))
+
+	if len(sf.Bytes()) == 0 {
+		t.Errorf("expected non-empty Bytes()")
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	f, _ := bloom.NewXXH3Filter(1000, 4)
+	sf := NewSyncFilter(f, 4)
+	sf.Add([]byte("one"))
+
+	snap := sf.Snapshot()
+	snap.SetHashFunction128(f.HashFunction128())
+	if !snap.Test([]byte("one")) {
+		t.Errorf("snapshot should contain []byte(\"one\")")
+	}
+
+	sf.Add([]byte("two"))
+	if snap.Test([]byte("two")) {
+		t.Errorf("snapshot should not be affected by later Add calls")
+	}
+}
+
+func TestNewSyncFilterDefaultStripes(t *testing.T) {
+	f, _ := bloom.NewXXH3Filter(1000, 4)
+	sf := NewSyncFilter(f, 0)
+	if len(sf.stripes) < 1 {
+		t.Errorf("expected at least 1 stripe, got %v", len(sf.stripes))
+	}
+}